@@ -1,43 +1,180 @@
 package main
 
 import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"math"
+	"math/rand"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/time/rate"
 )
 
 // --- Configuration ---
 var (
-	qbitHost  string
-	qbitUser  string
-	qbitPass  string
-	ntfyTopic string
-	pollInt   = 5 * time.Second
+	qbitHost     string
+	qbitUser     string
+	qbitPass     string
+	ntfyServer   string
+	ntfyTopic    string
+	ntfyUser     string
+	ntfyPass     string
+	ntfyToken    string
+	pollInt      = 5 * time.Second
+	autoDiscover bool
+	actionToken  string
+	sidecarURL   string
 )
 
+// ntfyClient is used for all ntfy requests instead of http.DefaultClient so
+// NTFY_INSECURE_SKIP_VERIFY/NTFY_CA_FILE can configure its TLS behavior.
+// Left as the zero-config default outside of main() (e.g. in tests).
+var ntfyClient = http.DefaultClient
+
 // --- State ---
 var (
-	activeMonitors = make(map[string]bool)
-	mutex          sync.Mutex
+	// activeMonitors maps a tracked hash to the channel its trackTorrent
+	// goroutine reads updates from. The maindata poller is the only writer.
+	activeMonitors = make(map[string]chan *Torrent)
+	// completedHashes records hashes trackTorrent has already sent a
+	// completion push for. A finished torrent keeps appearing in maindata
+	// diffs while seeding, so without this auto-discovery would treat it as
+	// newly added on every tick and re-fire "Download Complete" forever.
+	// Cleared once qBit reports the hash removed.
+	completedHashes = make(map[string]bool)
+	mutex           sync.Mutex
+)
+
+// --- Rate limiting ---
+// Unlimited by default; main() tightens these from QBIT_RPS/NTFY_RPS.
+var (
+	qbitLimiter = rate.NewLimiter(rate.Inf, 1)
+	ntfyLimiter = rate.NewLimiter(rate.Inf, 1)
+)
+
+// --- Health ---
+var (
+	lastPollSuccess   time.Time
+	lastPollSuccessMu sync.Mutex
+)
+
+func markPollSuccess() {
+	lastPollSuccessMu.Lock()
+	lastPollSuccess = time.Now()
+	lastPollSuccessMu.Unlock()
+}
+
+// --- Metrics ---
+var (
+	metricTorrentsTracked = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sidecar_torrents_tracked_total",
+		Help: "Total number of torrents the sidecar has started tracking.",
+	})
+	metricNtfySendErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sidecar_ntfy_send_errors_total",
+		Help: "Total number of failed ntfy notification sends.",
+	})
+	metricQbitAPIErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sidecar_qbit_api_errors_total",
+		Help: "Total number of failed qBittorrent API requests.",
+	})
+	metricActiveMonitors = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "sidecar_active_monitors",
+		Help: "Current number of torrents being actively monitored.",
+	})
+	metricTorrentProgress = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sidecar_torrent_progress_percent",
+		Help: "Current download progress percentage, per torrent.",
+	}, []string{"hash", "name"})
 )
 
+func init() {
+	prometheus.MustRegister(
+		metricTorrentsTracked,
+		metricNtfySendErrors,
+		metricQbitAPIErrors,
+		metricActiveMonitors,
+		metricTorrentProgress,
+	)
+}
+
 // Torrent struct for JSON parsing
 type Torrent struct {
-	Hash     string  `json:"hash"`
-	Name     string  `json:"name"`
-	Progress float64 `json:"progress"`
-	Eta      int     `json:"eta"`
-	DlSpeed  int     `json:"dlspeed"`
-	State    string  `json:"state"`
+	Hash       string  `json:"hash"`
+	Name       string  `json:"name"`
+	Progress   float64 `json:"progress"`
+	Eta        int     `json:"eta"`
+	DlSpeed    int     `json:"dlspeed"`
+	State      string  `json:"state"`
+	Size       int64   `json:"size"`
+	Downloaded int64   `json:"downloaded"`
+	Uploaded   int64   `json:"uploaded"`
+	Ratio      float64 `json:"ratio"`
+	NumSeeds   int     `json:"num_seeds"`
+	NumLeechs  int     `json:"num_leechs"`
+	Completed  int64   `json:"completed"`
+}
+
+// MaindataResponse mirrors the shape of /api/v2/sync/maindata. Past the
+// initial full_update, qBit only sends the fields of each torrent that
+// changed since the last rid, so Torrents is decoded field-by-field rather
+// than straight into Torrent — see mergeTorrentDelta.
+type MaindataResponse struct {
+	Rid             int                        `json:"rid"`
+	FullUpdate      bool                       `json:"full_update"`
+	Torrents        map[string]json.RawMessage `json:"torrents"`
+	TorrentsRemoved []string                   `json:"torrents_removed"`
+}
+
+// mergeTorrentDelta merges a maindata partial update's raw fields onto the
+// last known full state for hash and decodes the result. Without this,
+// fields absent from a given tick's delta (because they haven't changed)
+// would decode as zero values on a fresh Torrent{} instead of carrying
+// forward their last known value.
+func mergeTorrentDelta(snapshots map[string]map[string]json.RawMessage, hash string, delta json.RawMessage) (*Torrent, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(delta, &fields); err != nil {
+		return nil, err
+	}
+
+	snap, ok := snapshots[hash]
+	if !ok {
+		snap = make(map[string]json.RawMessage)
+		snapshots[hash] = snap
+	}
+	for k, v := range fields {
+		snap[k] = v
+	}
+
+	merged, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Torrent
+	if err := json.Unmarshal(merged, &t); err != nil {
+		return nil, err
+	}
+	// maindata's torrents object is keyed by hash; unlike torrents/info, the
+	// per-torrent value itself carries no "hash" field, so it must be filled
+	// in from the map key.
+	t.Hash = hash
+	return &t, nil
 }
 
 func main() {
@@ -47,10 +184,27 @@ func main() {
 	qbitHost = getEnv("QBIT_HOST", "http://localhost:8080")
 	qbitUser = mustGetEnv("QBIT_USER")
 	qbitPass = mustGetEnv("QBIT_PASS")
+	ntfyServer = getEnv("NTFY_SERVER", "https://ntfy.sh")
 	ntfyTopic = mustGetEnv("NTFY_TOPIC")
+	ntfyUser = getEnv("NTFY_USER", "")
+	ntfyPass = getEnv("NTFY_PASS", "")
+	ntfyToken = getEnv("NTFY_TOKEN", "")
+	ntfyClient = newNtfyClient()
+	autoDiscover = getEnv("AUTO_DISCOVER", "false") == "true"
+	actionToken = mustGetEnv("ACTION_TOKEN")
+	sidecarURL = getEnv("SIDECAR_URL", "http://localhost:9090")
+	qbitLimiter = newRateLimiter("QBIT_RPS")
+	ntfyLimiter = newRateLimiter("NTFY_RPS")
 
 	// 2. Start Trigger Server
 	http.HandleFunc("/track", handleTrackRequest)
+	http.HandleFunc("/action", handleActionRequest)
+	http.HandleFunc("/healthz", handleHealthz)
+	http.Handle("/metrics", promhttp.Handler())
+
+	// 3. Single shared poller: fans maindata diffs out to tracked hashes and,
+	// if auto-discovery is enabled, starts tracking hashes it hasn't seen.
+	go runMaindataPoller()
 
 	port := "9090"
 	log.Printf("Sidecar listening on :%s", port)
@@ -70,104 +224,422 @@ func handleTrackRequest(w http.ResponseWriter, r *http.Request) {
 	}
 
 	mutex.Lock()
-	if activeMonitors[hash] {
+	if _, ok := activeMonitors[hash]; ok {
 		mutex.Unlock()
 		fmt.Fprintf(w, "Already tracking %s", hash)
 		return
 	}
-	activeMonitors[hash] = true
+	updates := make(chan *Torrent, 1)
+	activeMonitors[hash] = updates
 	mutex.Unlock()
 
-	go trackTorrent(hash)
+	metricTorrentsTracked.Inc()
+	metricActiveMonitors.Inc()
+
+	go trackTorrent(hash, updates)
 
 	w.WriteHeader(200)
 	fmt.Fprintf(w, "Tracking started for %s", hash)
 }
 
-func trackTorrent(hash string) {
-	defer func() {
-		mutex.Lock()
-		delete(activeMonitors, hash)
-		mutex.Unlock()
-	}()
+// handleHealthz reports healthy only if the poller has logged in or synced
+// with qBittorrent recently, so a stuck or unreachable qBit instance shows
+// up in the same K8s liveness/readiness checks the sidecar is deployed
+// alongside.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	lastPollSuccessMu.Lock()
+	last := lastPollSuccess
+	lastPollSuccessMu.Unlock()
+
+	if time.Since(last) > 2*pollInt {
+		http.Error(w, "stale qBit connection", 503)
+		return
+	}
+	w.WriteHeader(200)
+	fmt.Fprint(w, "ok")
+}
 
-	log.Printf("[%s] Monitor started", hash)
+var qbitActionPaths = map[string]string{
+	"pause":  "/api/v2/torrents/pause",
+	"resume": "/api/v2/torrents/resume",
+	"delete": "/api/v2/torrents/delete",
+}
+
+// handleActionRequest proxies a tap on an ntfy action button through to
+// qBittorrent, so notifications double as a lightweight remote control
+// without exposing the WebUI itself.
+func handleActionRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", 405)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(actionToken)) != 1 {
+		http.Error(w, "Unauthorized", 401)
+		return
+	}
+
+	hash := r.URL.Query().Get("hash")
+	op := r.URL.Query().Get("op")
+	if hash == "" || op == "" {
+		http.Error(w, "Missing 'hash' or 'op' query parameter", 400)
+		return
+	}
+
+	path, ok := qbitActionPaths[op]
+	if !ok {
+		http.Error(w, "Unknown op", 400)
+		return
+	}
 
-	// Per-routine client to handle independent auth sessions cleanly
 	jar, _ := cookiejar.New(nil)
 	client := &http.Client{Jar: jar, Timeout: 5 * time.Second}
-
 	if err := login(client); err != nil {
-		log.Printf("[%s] Auth failed: %v", hash, err)
+		metricQbitAPIErrors.Inc()
+		http.Error(w, "qBit auth failed", 502)
 		return
 	}
 
+	data := url.Values{}
+	data.Set("hashes", hash)
+
+	if err := qbitLimiter.Wait(r.Context()); err != nil {
+		http.Error(w, "rate limited", 500)
+		return
+	}
+	resp, err := client.PostForm(qbitHost+path, data)
+	if err != nil {
+		metricQbitAPIErrors.Inc()
+		log.Printf("[%s] Action %q failed: %v", hash, op, err)
+		http.Error(w, "qBit request failed", 502)
+		return
+	}
+	defer resp.Body.Close()
+
+	w.WriteHeader(200)
+	fmt.Fprintf(w, "%s: %s", op, hash)
+}
+
+// runMaindataPoller is the single background loop that talks to
+// qBittorrent. It replaces the old one-goroutine-per-torrent polling
+// design: every tick it pulls an incremental maindata diff off one shared,
+// already-authenticated client and fans the per-hash updates out to
+// whichever trackTorrent goroutines are subscribed via activeMonitors,
+// spawning new ones itself when auto-discovery is enabled.
+func runMaindataPoller() {
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar, Timeout: 5 * time.Second}
+
+	bo := &backoff{}
+	for {
+		if err := login(client); err != nil {
+			metricQbitAPIErrors.Inc()
+			log.Printf("[poller] Auth failed: %v", err)
+			time.Sleep(bo.next())
+			continue
+		}
+		break
+	}
+	bo.reset()
+	markPollSuccess()
+
+	log.Printf("[poller] Watching %s", qbitHost)
+
 	ticker := time.NewTicker(pollInt)
 	defer ticker.Stop()
 
-	lastPct := -1
+	// snapshots holds the last known full state per hash so partial diffs
+	// can be merged onto it; owned entirely by this goroutine.
+	snapshots := make(map[string]map[string]json.RawMessage)
 
+	rid := 0
 	for range ticker.C {
-		t, err := getTorrentInfo(client, hash)
+		md, err := getMaindata(client, rid)
 		if err != nil {
-			log.Printf("[%s] Error: %v", hash, err)
+			metricQbitAPIErrors.Inc()
+			log.Printf("[poller] Error: %v", err)
+			time.Sleep(bo.next())
 			continue
 		}
-		if t == nil {
-			log.Printf("[%s] Torrent removed. Stopping.", hash)
-			return
+		bo.reset()
+		markPollSuccess()
+		rid = md.Rid
+
+		processMaindataTick(md, snapshots)
+	}
+}
+
+// processMaindataTick merges one maindata poll's deltas onto snapshots and
+// dispatches each torrent's latest state, or removes torrents/monitors qBit
+// reports as gone. Split out from runMaindataPoller so the dispatch/seeding
+// logic can be exercised without a live qBit connection.
+func processMaindataTick(md *MaindataResponse, snapshots map[string]map[string]json.RawMessage) {
+	for hash, delta := range md.Torrents {
+		t, err := mergeTorrentDelta(snapshots, hash, delta)
+		if err != nil {
+			log.Printf("[poller] Failed to decode torrent %s: %v", hash, err)
+			continue
+		}
+
+		// A torrent that's already finished on the very first snapshot this
+		// process observes (e.g. right after a pod restart) must not be
+		// treated as newly discovered, or auto-discovery would re-announce
+		// completion for the entire library on every redeploy. Seed it
+		// straight into completedHashes instead of dispatching.
+		if md.FullUpdate && isTorrentComplete(t) {
+			mutex.Lock()
+			completedHashes[hash] = true
+			mutex.Unlock()
+			continue
+		}
+
+		dispatchUpdate(hash, t)
+	}
+
+	for _, hash := range md.TorrentsRemoved {
+		delete(snapshots, hash)
+
+		mutex.Lock()
+		if ch, ok := activeMonitors[hash]; ok {
+			delete(activeMonitors, hash)
+			close(ch)
+		}
+		delete(completedHashes, hash)
+		mutex.Unlock()
+	}
+}
+
+// dispatchUpdate routes a torrent's latest state to its subscriber,
+// spawning one via auto-discovery if none exists yet.
+func dispatchUpdate(hash string, t *Torrent) {
+	mutex.Lock()
+	ch, tracked := activeMonitors[hash]
+	spawned := !tracked && autoDiscover && !completedHashes[hash]
+	if spawned {
+		log.Printf("[auto-discover] New torrent detected: %s", hash)
+		ch = make(chan *Torrent, 1)
+		activeMonitors[hash] = ch
+		tracked = true
+		go trackTorrent(hash, ch)
+	}
+	mutex.Unlock()
+
+	if spawned {
+		metricTorrentsTracked.Inc()
+		metricActiveMonitors.Inc()
+	}
+
+	if !tracked {
+		return
+	}
+
+	select {
+	case ch <- t:
+	default:
+		// Subscriber hasn't drained the previous tick; drop rather than
+		// block the poller over one slow consumer.
+	}
+}
+
+// isTorrentComplete reports whether t's progress/state indicates it has
+// finished downloading. qBittorrent states: upload, uploading, upLO,
+// pausedUP, completed, etc.
+func isTorrentComplete(t *Torrent) bool {
+	pct := int(t.Progress * 100)
+	return pct >= 100 || strings.Contains(t.State, "up") || t.State == "completed"
+}
+
+func trackTorrent(hash string, updates chan *Torrent) {
+	name := hash
+
+	defer func() {
+		mutex.Lock()
+		delete(activeMonitors, hash)
+		mutex.Unlock()
+
+		metricActiveMonitors.Dec()
+		metricTorrentProgress.DeleteLabelValues(hash, name)
+	}()
+
+	log.Printf("[%s] Monitor started", hash)
+
+	lastPct := -1
+	lastDownloaded := int64(-1)
+
+	for t := range updates {
+		name = t.Name
+
+		// Average over the tick interval rather than trusting qBit's
+		// instantaneous dlspeed, which is noisy at short intervals.
+		var avgRate float64
+		if lastDownloaded >= 0 {
+			avgRate = float64(t.Downloaded-lastDownloaded) / pollInt.Seconds()
 		}
+		lastDownloaded = t.Downloaded
 
 		pct := int(t.Progress * 100)
+		metricTorrentProgress.WithLabelValues(hash, t.Name).Set(float64(pct))
 
 		// Update Notification if progress changed
 		if pct > lastPct {
 			lastPct = pct
-			sendUpdate(t, pct)
+			sendUpdate(t, pct, avgRate)
 		}
 
 		// Check Completion
-		// qBittorrent states: upload, uploading, upLO, pausedUP, completed, etc.
-		if pct >= 100 || strings.Contains(t.State, "up") || t.State == "completed" {
+		if isTorrentComplete(t) {
 			sendComplete(t)
+			mutex.Lock()
+			completedHashes[hash] = true
+			mutex.Unlock()
 			return
 		}
 	}
+
+	log.Printf("[%s] Torrent removed. Stopping.", hash)
 }
 
-func sendUpdate(t *Torrent, pct int) {
+func sendUpdate(t *Torrent, pct int, avgRate float64) {
 	bar := drawProgressBar(pct)
 	speed := float64(t.DlSpeed) / 1024 / 1024
+	avg := avgRate / 1024 / 1024
 	eta := formatDuration(t.Eta)
 
-	msg := fmt.Sprintf("%d%% %s\nSpeed: %.1f MB/s\nETA: %s", pct, bar, speed, eta)
+	// Mirrors anacrolix/torrent's torrentBar: "<done>/<total>, seeds S/L,
+	// ratio R, rate (avg rate)".
+	msg := fmt.Sprintf(
+		"%d%% %s\n%s/%s, seeds %d/%d, ratio %.2f\n%.1f MB/s (avg %.1f MB/s)\nETA: %s",
+		pct, bar, humanizeBytes(t.Completed), humanizeBytes(t.Size), t.NumSeeds, t.NumLeechs, t.Ratio, speed, avg, eta,
+	)
+
+	actions := []NtfyAction{
+		{Label: "Pause", URL: actionURL(t.Hash, "pause"), Clear: true},
+		{Label: "Resume", URL: actionURL(t.Hash, "resume"), Clear: true},
+	}
 
 	// Priority 'default' (3) is silent on most clients
-	sendNtfy(t.Name, msg, "arrow_down", "qbit-"+t.Hash, "default")
+	sendNtfy(t.Name, msg, "arrow_down", "qbit-"+t.Hash, "default", actions)
 }
 
 func sendComplete(t *Torrent) {
+	actions := []NtfyAction{
+		{Label: "Delete", URL: actionURL(t.Hash, "delete"), Clear: true},
+	}
+
 	// Priority 'high' (4) triggers vibration/sound
-	sendNtfy("Download Complete", t.Name+" has finished downloading.", "white_check_mark", "qbit-"+t.Hash, "high")
+	sendNtfy("Download Complete", t.Name+" has finished downloading.", "white_check_mark", "qbit-"+t.Hash, "high", actions)
+}
+
+// NtfyAction models one "http"-type button in ntfy's Actions header, e.g.
+// a Pause/Resume/Delete control that posts back to the sidecar.
+type NtfyAction struct {
+	Label string
+	URL   string
+	Clear bool // close the notification once tapped
+}
+
+// String renders the action in ntfy's Actions header format: the action
+// type, label and url are positional, with any extra params (like
+// clear=true) trailing as key=value pairs.
+func (a NtfyAction) String() string {
+	parts := []string{"http", a.Label, a.URL}
+	if a.Clear {
+		parts = append(parts, "clear=true")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// actionURL builds the sidecar's own /action callback URL for an ntfy
+// button to hit.
+func actionURL(hash, op string) string {
+	q := url.Values{}
+	q.Set("hash", hash)
+	q.Set("op", op)
+	q.Set("token", actionToken)
+	return sidecarURL + "/action?" + q.Encode()
 }
 
-func sendNtfy(title, msg, tag, id, priority string) {
-	req, _ := http.NewRequest("POST", ntfyTopic, strings.NewReader(msg))
+// topicURL resolves the ntfy POST endpoint. NTFY_TOPIC has historically held
+// the full POST URL, so that form is preserved as-is; otherwise it's treated
+// as a bare topic name appended to NTFY_SERVER.
+func topicURL() string {
+	if strings.HasPrefix(ntfyTopic, "http://") || strings.HasPrefix(ntfyTopic, "https://") {
+		return ntfyTopic
+	}
+	return strings.TrimRight(ntfyServer, "/") + "/" + ntfyTopic
+}
+
+func sendNtfy(title, msg, tag, id, priority string, actions []NtfyAction) {
+	if err := ntfyLimiter.Wait(context.Background()); err != nil {
+		log.Printf("ntfy rate limiter: %v", err)
+		return
+	}
+
+	req, _ := http.NewRequest("POST", topicURL(), strings.NewReader(msg))
 	req.Header.Set("Title", title)
 	req.Header.Set("Tags", tag)
 	req.Header.Set("Priority", priority)
 	req.Header.Set("X-Notification-ID", id) // Updates in-place
 
-	resp, err := http.DefaultClient.Do(req)
+	switch {
+	case ntfyToken != "":
+		req.Header.Set("Authorization", "Bearer "+ntfyToken)
+	case ntfyUser != "":
+		req.SetBasicAuth(ntfyUser, ntfyPass)
+	}
+
+	if len(actions) > 0 {
+		parts := make([]string, len(actions))
+		for i, a := range actions {
+			parts[i] = a.String()
+		}
+		req.Header.Set("Actions", strings.Join(parts, "; "))
+	}
+
+	resp, err := ntfyClient.Do(req)
 	if err != nil {
+		metricNtfySendErrors.Inc()
 		log.Printf("Failed to send ntfy notification: %v", err)
 		return
 	}
 	defer resp.Body.Close()
 }
 
+// newNtfyClient builds the *http.Client used for all ntfy requests,
+// applying NTFY_INSECURE_SKIP_VERIFY and NTFY_CA_FILE for self-hosted
+// instances behind a private CA.
+func newNtfyClient() *http.Client {
+	insecureSkipVerify := getEnv("NTFY_INSECURE_SKIP_VERIFY", "false") == "true"
+	caFile := getEnv("NTFY_CA_FILE", "")
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			log.Fatalf("Failed to read NTFY_CA_FILE: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			log.Fatalf("NTFY_CA_FILE does not contain a valid PEM certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+}
+
+// qbitGet issues a rate-limited GET against the qBit API and returns the
+// raw response, so callers only need to handle decoding.
+func qbitGet(client *http.Client, path string) (*http.Response, error) {
+	if err := qbitLimiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+	return client.Get(qbitHost + path)
+}
+
 func getTorrentInfo(client *http.Client, hash string) (*Torrent, error) {
-	resp, err := client.Get(qbitHost + "/api/v2/torrents/info?hashes=" + hash)
+	resp, err := qbitGet(client, "/api/v2/torrents/info?hashes="+hash)
 	if err != nil {
 		return nil, err
 	}
@@ -188,6 +660,24 @@ func getTorrentInfo(client *http.Client, hash string) (*Torrent, error) {
 	return &torrents[0], nil
 }
 
+func getMaindata(client *http.Client, rid int) (*MaindataResponse, error) {
+	resp, err := qbitGet(client, fmt.Sprintf("/api/v2/sync/maindata?rid=%d", rid))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("qBit API returned status: %d", resp.StatusCode)
+	}
+
+	var md MaindataResponse
+	if err := json.NewDecoder(resp.Body).Decode(&md); err != nil {
+		return nil, err
+	}
+	return &md, nil
+}
+
 func login(client *http.Client) error {
 	data := url.Values{}
 	data.Set("username", qbitUser)
@@ -212,10 +702,26 @@ func drawProgressBar(pct int) string {
 	if filled > width {
 		filled = width
 	}
+	if filled < 0 {
+		filled = 0
+	}
 	empty := width - filled
 	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", empty) + "]"
 }
 
+func humanizeBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for n2 := n / unit; n2 >= unit; n2 /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func formatDuration(sec int) string {
 	if sec >= 8640000 {
 		return "∞"
@@ -223,6 +729,41 @@ func formatDuration(sec int) string {
 	return (time.Duration(sec) * time.Second).String()
 }
 
+// backoff implements capped exponential backoff with jitter, used so a
+// restarting qBittorrent doesn't get hammered with retries the moment it
+// comes back up.
+type backoff struct {
+	attempt int
+}
+
+func (b *backoff) next() time.Duration {
+	d := time.Duration(1<<uint(b.attempt)) * time.Second
+	if d >= 60*time.Second {
+		d = 60 * time.Second
+	} else {
+		b.attempt++
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+func (b *backoff) reset() {
+	b.attempt = 0
+}
+
+// newRateLimiter builds a limiter from an env var holding requests/sec;
+// an unset or empty value means unlimited.
+func newRateLimiter(envKey string) *rate.Limiter {
+	v := os.Getenv(envKey)
+	if v == "" {
+		return rate.NewLimiter(rate.Inf, 1)
+	}
+	rps, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Fatalf("Invalid value for %s: %v", envKey, err)
+	}
+	return rate.NewLimiter(rate.Limit(rps), 1)
+}
+
 func mustGetEnv(k string) string {
 	v := os.Getenv(k)
 	if v == "" {
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 func TestDrawProgressBar(t *testing.T) {
@@ -48,6 +55,26 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		n        int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{1024 * 1024, "1.0 MiB"},
+		{1024 * 1024 * 1024, "1.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if result := humanizeBytes(tt.n); result != tt.expected {
+			t.Errorf("humanizeBytes(%d): expected %s, got %s", tt.n, tt.expected, result)
+		}
+	}
+}
+
 func TestGetTorrentInfo(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -135,6 +162,572 @@ func TestGetTorrentInfo(t *testing.T) {
 	}
 }
 
+func TestGetMaindata(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     func(w http.ResponseWriter, r *http.Request)
+		expectError bool
+		expectRid   int
+	}{
+		{
+			name: "Success",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("rid"); got != "5" {
+					t.Errorf("expected rid=5, got %q", got)
+				}
+				w.WriteHeader(200)
+				_, _ = fmt.Fprintln(w, `{"rid":6,"torrents":{"abc":{"progress":0.5}}}`)
+			},
+			expectError: false,
+			expectRid:   6,
+		},
+		{
+			name: "API Error (500)",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(500)
+				_, _ = fmt.Fprintln(w, `Internal Server Error`)
+			},
+			expectError: true,
+		},
+		{
+			name: "Malformed JSON",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+				_, _ = fmt.Fprintln(w, `{"rid": ... invalid ...`)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Path, "/api/v2/sync/maindata") {
+					tt.handler(w, r)
+					return
+				}
+				w.WriteHeader(404)
+			}))
+			defer ts.Close()
+
+			oldHost := qbitHost
+			qbitHost = ts.URL
+			defer func() { qbitHost = oldHost }()
+
+			md, err := getMaindata(ts.Client(), 5)
+
+			if tt.expectError && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.expectError && md.Rid != tt.expectRid {
+				t.Errorf("expected rid %d, got %d", tt.expectRid, md.Rid)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	b := &backoff{}
+
+	// Each successive delay should cap at 60s and never exceed the
+	// doubling schedule.
+	var prevCap time.Duration
+	for i := 0; i < 10; i++ {
+		cap := time.Duration(1<<uint(i)) * time.Second
+		if cap >= 60*time.Second {
+			cap = 60 * time.Second
+		}
+		d := b.next()
+		if d > cap {
+			t.Errorf("attempt %d: delay %v exceeded cap %v", i, d, cap)
+		}
+		if d < 0 {
+			t.Errorf("attempt %d: negative delay %v", i, d)
+		}
+		prevCap = cap
+	}
+	if prevCap != 60*time.Second {
+		t.Fatalf("expected backoff to have reached the 60s cap, got %v", prevCap)
+	}
+
+	b.reset()
+	if b.attempt != 0 {
+		t.Errorf("expected reset to zero the attempt counter, got %d", b.attempt)
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	const envKey = "TEST_RATE_LIMIT_RPS"
+	t.Cleanup(func() { os.Unsetenv(envKey) })
+
+	os.Unsetenv(envKey)
+	if lim := newRateLimiter(envKey); lim.Limit() != rate.Inf {
+		t.Errorf("expected unlimited rate when env unset, got %v", lim.Limit())
+	}
+
+	os.Setenv(envKey, "2.5")
+	if lim := newRateLimiter(envKey); lim.Limit() != rate.Limit(2.5) {
+		t.Errorf("expected rate 2.5, got %v", lim.Limit())
+	}
+}
+
+func TestDispatchUpdateAutoDiscovery(t *testing.T) {
+	oldAutoDiscover := autoDiscover
+	oldActiveMonitors := activeMonitors
+	oldCompletedHashes := completedHashes
+	t.Cleanup(func() {
+		mutex.Lock()
+		for _, ch := range activeMonitors {
+			close(ch)
+		}
+		mutex.Unlock()
+		autoDiscover = oldAutoDiscover
+		activeMonitors = oldActiveMonitors
+		completedHashes = oldCompletedHashes
+	})
+
+	t.Run("spawns a monitor for a newly seen hash", func(t *testing.T) {
+		autoDiscover = true
+		activeMonitors = make(map[string]chan *Torrent)
+		completedHashes = make(map[string]bool)
+
+		dispatchUpdate("new-hash", &Torrent{Hash: "new-hash", Name: "New Torrent"})
+
+		mutex.Lock()
+		_, tracked := activeMonitors["new-hash"]
+		mutex.Unlock()
+		if !tracked {
+			t.Error("expected dispatchUpdate to start tracking a newly seen hash")
+		}
+	})
+
+	t.Run("does not respawn a hash already marked completed", func(t *testing.T) {
+		autoDiscover = true
+		activeMonitors = make(map[string]chan *Torrent)
+		completedHashes = map[string]bool{"done-hash": true}
+
+		dispatchUpdate("done-hash", &Torrent{Hash: "done-hash", Name: "Done Torrent"})
+
+		mutex.Lock()
+		_, tracked := activeMonitors["done-hash"]
+		mutex.Unlock()
+		if tracked {
+			t.Error("expected a completed hash not to be re-discovered as a new torrent")
+		}
+	})
+
+	t.Run("does not spawn when auto-discovery is disabled", func(t *testing.T) {
+		autoDiscover = false
+		activeMonitors = make(map[string]chan *Torrent)
+		completedHashes = make(map[string]bool)
+
+		dispatchUpdate("some-hash", &Torrent{Hash: "some-hash"})
+
+		mutex.Lock()
+		_, tracked := activeMonitors["some-hash"]
+		mutex.Unlock()
+		if tracked {
+			t.Error("expected no tracking to start with auto-discovery disabled")
+		}
+	})
+
+	t.Run("routes an update to an already-tracked hash's channel", func(t *testing.T) {
+		autoDiscover = false
+		ch := make(chan *Torrent, 1)
+		activeMonitors = map[string]chan *Torrent{"tracked-hash": ch}
+		completedHashes = make(map[string]bool)
+
+		want := &Torrent{Hash: "tracked-hash", Name: "Tracked Torrent"}
+		dispatchUpdate("tracked-hash", want)
+
+		select {
+		case got := <-ch:
+			if got != want {
+				t.Errorf("expected routed torrent %+v, got %+v", want, got)
+			}
+		default:
+			t.Error("expected the update to be routed onto the subscriber's channel")
+		}
+	})
+}
+
+func TestProcessMaindataTickSeedsCompletedOnFirstSnapshot(t *testing.T) {
+	oldAutoDiscover := autoDiscover
+	oldActiveMonitors := activeMonitors
+	oldCompletedHashes := completedHashes
+	t.Cleanup(func() {
+		mutex.Lock()
+		for _, ch := range activeMonitors {
+			close(ch)
+		}
+		mutex.Unlock()
+		autoDiscover = oldAutoDiscover
+		activeMonitors = oldActiveMonitors
+		completedHashes = oldCompletedHashes
+	})
+
+	autoDiscover = true
+	activeMonitors = make(map[string]chan *Torrent)
+	completedHashes = make(map[string]bool)
+	snapshots := make(map[string]map[string]json.RawMessage)
+
+	// The very first poll after a process start is always a full_update.
+	// A torrent that's already finished must not be auto-discovered as
+	// new, or it would fire a spurious "Download Complete" push on every
+	// redeploy of an already-populated library.
+	md := &MaindataResponse{
+		FullUpdate: true,
+		Torrents: map[string]json.RawMessage{
+			"done-hash": json.RawMessage(`{"name":"Finished Torrent","progress":1,"state":"pausedUP"}`),
+		},
+	}
+	processMaindataTick(md, snapshots)
+
+	mutex.Lock()
+	_, tracked := activeMonitors["done-hash"]
+	alreadyCompleted := completedHashes["done-hash"]
+	mutex.Unlock()
+
+	if tracked {
+		t.Error("expected an already-finished torrent not to be auto-discovered on the first snapshot")
+	}
+	if !alreadyCompleted {
+		t.Error("expected an already-finished torrent to be seeded into completedHashes")
+	}
+}
+
+func TestMergeTorrentDelta(t *testing.T) {
+	snapshots := make(map[string]map[string]json.RawMessage)
+
+	// First tick: full_update-style delta with every field present. Real
+	// maindata deltas are keyed by hash and never repeat it inside the
+	// per-torrent value (unlike the old torrents/info array), so the
+	// fixture deliberately omits "hash".
+	first := json.RawMessage(`{"name":"Test Torrent","progress":0.5,"size":1000,"downloaded":500,"num_seeds":3,"num_leechs":1}`)
+	t1, err := mergeTorrentDelta(snapshots, "abc", first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if t1.Size != 1000 || t1.Downloaded != 500 || t1.NumSeeds != 3 {
+		t.Errorf("unexpected initial merge result: %+v", t1)
+	}
+	if t1.Hash != "abc" {
+		t.Errorf("expected hash to be filled in from the map key, got %q", t1.Hash)
+	}
+
+	// Second tick: partial delta only carries the fields that changed.
+	// Size/NumSeeds are unchanged and so absent; they must carry forward
+	// from the prior snapshot rather than decode as zero.
+	second := json.RawMessage(`{"progress":0.75,"downloaded":750}`)
+	t2, err := mergeTorrentDelta(snapshots, "abc", second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if t2.Downloaded != 750 {
+		t.Errorf("expected downloaded=750, got %d", t2.Downloaded)
+	}
+	if t2.Size != 1000 {
+		t.Errorf("expected size to carry forward as 1000, got %d", t2.Size)
+	}
+	if t2.NumSeeds != 3 {
+		t.Errorf("expected num_seeds to carry forward as 3, got %d", t2.NumSeeds)
+	}
+	if t2.Name != "Test Torrent" {
+		t.Errorf("expected name to carry forward, got %q", t2.Name)
+	}
+	if t2.Hash != "abc" {
+		t.Errorf("expected hash to still be filled in on a partial delta, got %q", t2.Hash)
+	}
+}
+
+func TestNtfyActionString(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   NtfyAction
+		expected string
+	}{
+		{
+			name:     "without clear",
+			action:   NtfyAction{Label: "Pause", URL: "https://example.com/action?op=pause"},
+			expected: "http, Pause, https://example.com/action?op=pause",
+		},
+		{
+			name:     "with clear",
+			action:   NtfyAction{Label: "Delete", URL: "https://example.com/action?op=delete", Clear: true},
+			expected: "http, Delete, https://example.com/action?op=delete, clear=true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.action.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestActionURL(t *testing.T) {
+	oldSidecarURL := sidecarURL
+	oldActionToken := actionToken
+	t.Cleanup(func() {
+		sidecarURL = oldSidecarURL
+		actionToken = oldActionToken
+	})
+
+	sidecarURL = "http://sidecar:9090"
+	actionToken = "a&b=c#d%e"
+
+	got := actionURL("abc123", "pause")
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("actionURL produced an unparseable URL: %v", err)
+	}
+
+	q := u.Query()
+	if q.Get("hash") != "abc123" {
+		t.Errorf("expected hash=abc123, got %q", q.Get("hash"))
+	}
+	if q.Get("op") != "pause" {
+		t.Errorf("expected op=pause, got %q", q.Get("op"))
+	}
+	if q.Get("token") != actionToken {
+		t.Errorf("expected token %q to survive escaping, got %q", actionToken, q.Get("token"))
+	}
+}
+
+func TestHandleActionRequest(t *testing.T) {
+	oldHost := qbitHost
+	oldToken := actionToken
+	t.Cleanup(func() {
+		qbitHost = oldHost
+		actionToken = oldToken
+	})
+	actionToken = "secret-token"
+
+	newQbitServer := func(t *testing.T, loginOK bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.Contains(r.URL.Path, "/api/v2/auth/login"):
+				if !loginOK {
+					w.WriteHeader(403)
+					return
+				}
+				w.WriteHeader(200)
+				_, _ = fmt.Fprint(w, "Ok.")
+			case strings.Contains(r.URL.Path, "/api/v2/torrents/pause"):
+				if err := r.ParseForm(); err != nil || r.FormValue("hashes") != "abc123" {
+					t.Errorf("expected hashes=abc123 form value, got %q (err=%v)", r.FormValue("hashes"), err)
+				}
+				w.WriteHeader(200)
+			default:
+				w.WriteHeader(404)
+			}
+		}))
+	}
+
+	t.Run("rejects a bad token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/action?hash=abc123&op=pause&token=wrong", nil)
+		rec := httptest.NewRecorder()
+		handleActionRequest(rec, req)
+		if rec.Code != 401 {
+			t.Errorf("expected 401, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects a missing hash or op", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/action?op=pause&token=secret-token", nil)
+		rec := httptest.NewRecorder()
+		handleActionRequest(rec, req)
+		if rec.Code != 400 {
+			t.Errorf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rejects an unknown op", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/action?hash=abc123&op=nuke&token=secret-token", nil)
+		rec := httptest.NewRecorder()
+		handleActionRequest(rec, req)
+		if rec.Code != 400 {
+			t.Errorf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("proxies a valid op through to qBit", func(t *testing.T) {
+		ts := newQbitServer(t, true)
+		defer ts.Close()
+		qbitHost = ts.URL
+
+		req := httptest.NewRequest("POST", "/action?hash=abc123&op=pause&token=secret-token", nil)
+		rec := httptest.NewRecorder()
+		handleActionRequest(rec, req)
+		if rec.Code != 200 {
+			t.Errorf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("surfaces a qBit auth failure as 502", func(t *testing.T) {
+		ts := newQbitServer(t, false)
+		defer ts.Close()
+		qbitHost = ts.URL
+
+		req := httptest.NewRequest("POST", "/action?hash=abc123&op=pause&token=secret-token", nil)
+		rec := httptest.NewRecorder()
+		handleActionRequest(rec, req)
+		if rec.Code != 502 {
+			t.Errorf("expected 502, got %d", rec.Code)
+		}
+	})
+}
+
+// testCAPEM is a throwaway self-signed cert, valid only for exercising
+// newNtfyClient's NTFY_CA_FILE parsing.
+const testCAPEM = `-----BEGIN CERTIFICATE-----
+MIIDBTCCAe2gAwIBAgIUZaayT/4u/ThK0kgDme97ZNT0XYgwDQYJKoZIhvcNAQEL
+BQAwEjEQMA4GA1UEAwwHdGVzdC1jYTAeFw0yNjA3MjUyMDI5MDJaFw0zNjA3MjIy
+MDI5MDJaMBIxEDAOBgNVBAMMB3Rlc3QtY2EwggEiMA0GCSqGSIb3DQEBAQUAA4IB
+DwAwggEKAoIBAQDGkVKzB3atZ9Np/60AuUKK5a9DHEqBc588ZBeUVpRxK/g1eiFW
+gOF6gchkXoX9UxHVU96clYSrvtqSiZ5pWnD8JkULSxMJq6r6t7zdOYCMA9lL5REf
++m7V7lFnU7gYU81lBIV8mOjwcasWEoUTKwRnJiCeWh56BWnWg6w7GNgqwk9Id99V
+P+gth4Oy2OL/cQF05Ka9OTC2sTunh1gAjWwFRb4PuPMfwRNYTEALbA3rE7J1Z6A3
+VPwSC3LYEsWMPa4DvfGbSUMCGdcSlQBaWWVYJnTwOBZMkZPIIrjNPJo2mpKGxSUP
+LaTOxZGg/O8K/YIuiYoS8koKgqLJT3JikMqfAgMBAAGjUzBRMB0GA1UdDgQWBBRZ
+VTbDL0PmzbPt8jrFhgu8qNsM5zAfBgNVHSMEGDAWgBRZVTbDL0PmzbPt8jrFhgu8
+qNsM5zAPBgNVHRMBAf8EBTADAQH/MA0GCSqGSIb3DQEBCwUAA4IBAQCVjSZ3LMMk
+D/ACLkviWkVAU6AftjqkCpRPBmu2kljLXHwS0AuPPF+52R9K9R0m0CvnNgJEpxPh
+acG9oVGRLNa8Gd+UmYF6Fl6jDZuQNosELaV0EeeJEl8bHDScl0wNhZbLtasNsSsg
+fqnCf595uWTOJpRi+dm9udZP/K0q20GwIYAmcbPo1OSCqKdh9B+dWHAnDa5kdM8u
+zqyX5Vf6P0BgXgsV+G7CII35DPMEK7NcuzQNTsYFwgFcPIUin5kPkgRXGFN6S7Y/
+TlmyQjEVnEFX1rvaJSBOLpnCiSELEuz+r4nDFwJqdRQ8s0/8kxesScVXp668Ru1d
+Dhe/8g2BFknH
+-----END CERTIFICATE-----
+`
+
+func TestNewNtfyClient(t *testing.T) {
+	for _, k := range []string{"NTFY_INSECURE_SKIP_VERIFY", "NTFY_CA_FILE"} {
+		old, had := os.LookupEnv(k)
+		t.Cleanup(func() {
+			if had {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+
+	t.Run("defaults to verifying certs", func(t *testing.T) {
+		os.Unsetenv("NTFY_INSECURE_SKIP_VERIFY")
+		os.Unsetenv("NTFY_CA_FILE")
+
+		client := newNtfyClient()
+		tr := client.Transport.(*http.Transport)
+		if tr.TLSClientConfig.InsecureSkipVerify {
+			t.Error("expected InsecureSkipVerify to default to false")
+		}
+		if tr.TLSClientConfig.RootCAs != nil {
+			t.Error("expected RootCAs to be nil without NTFY_CA_FILE")
+		}
+	})
+
+	t.Run("honors NTFY_INSECURE_SKIP_VERIFY", func(t *testing.T) {
+		os.Setenv("NTFY_INSECURE_SKIP_VERIFY", "true")
+		t.Cleanup(func() { os.Unsetenv("NTFY_INSECURE_SKIP_VERIFY") })
+
+		client := newNtfyClient()
+		tr := client.Transport.(*http.Transport)
+		if !tr.TLSClientConfig.InsecureSkipVerify {
+			t.Error("expected InsecureSkipVerify to be true")
+		}
+	})
+
+	t.Run("loads a custom CA from NTFY_CA_FILE", func(t *testing.T) {
+		caPath := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caPath, []byte(testCAPEM), 0o600); err != nil {
+			t.Fatalf("failed to write test CA file: %v", err)
+		}
+		os.Setenv("NTFY_CA_FILE", caPath)
+		t.Cleanup(func() { os.Unsetenv("NTFY_CA_FILE") })
+
+		client := newNtfyClient()
+		tr := client.Transport.(*http.Transport)
+		if tr.TLSClientConfig.RootCAs == nil {
+			t.Error("expected RootCAs to be populated from NTFY_CA_FILE")
+		}
+	})
+}
+
+func TestTopicURL(t *testing.T) {
+	oldServer := ntfyServer
+	oldTopic := ntfyTopic
+	t.Cleanup(func() {
+		ntfyServer = oldServer
+		ntfyTopic = oldTopic
+	})
+
+	tests := []struct {
+		name     string
+		server   string
+		topic    string
+		expected string
+	}{
+		{"bare topic", "https://ntfy.sh", "mytopic", "https://ntfy.sh/mytopic"},
+		{"server trailing slash", "https://ntfy.sh/", "mytopic", "https://ntfy.sh/mytopic"},
+		{"full url topic preserved", "https://ntfy.sh", "https://ntfy.example.com/legacy-topic", "https://ntfy.example.com/legacy-topic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ntfyServer = tt.server
+			ntfyTopic = tt.topic
+			if got := topicURL(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHandleHealthz(t *testing.T) {
+	oldLastPollSuccess := lastPollSuccess
+	oldPollInt := pollInt
+	t.Cleanup(func() {
+		lastPollSuccessMu.Lock()
+		lastPollSuccess = oldLastPollSuccess
+		lastPollSuccessMu.Unlock()
+		pollInt = oldPollInt
+	})
+	pollInt = 5 * time.Second
+
+	t.Run("healthy after a recent poll", func(t *testing.T) {
+		markPollSuccess()
+
+		req := httptest.NewRequest("GET", "/healthz", nil)
+		rec := httptest.NewRecorder()
+		handleHealthz(rec, req)
+
+		if rec.Code != 200 {
+			t.Errorf("expected 200, got %d", rec.Code)
+		}
+	})
+
+	t.Run("unhealthy once the last poll is stale", func(t *testing.T) {
+		lastPollSuccessMu.Lock()
+		lastPollSuccess = time.Now().Add(-3 * pollInt)
+		lastPollSuccessMu.Unlock()
+
+		req := httptest.NewRequest("GET", "/healthz", nil)
+		rec := httptest.NewRecorder()
+		handleHealthz(rec, req)
+
+		if rec.Code != 503 {
+			t.Errorf("expected 503, got %d", rec.Code)
+		}
+	})
+}
+
 func TestSendNtfy(t *testing.T) {
 	// Mock Ntfy Server
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -174,11 +767,11 @@ func TestSendNtfy(t *testing.T) {
 	ntfyTopic = "test_topic"
 
 	// 1. Test standard notification (no auth)
-	sendNtfy("Test Title", "Test Message", "tag", "id", "3")
+	sendNtfy("Test Title", "Test Message", "tag", "id", "3", nil)
 
 	// 2. Test authenticated notification
 	ntfyTopic = "auth_topic"
 	ntfyUser = "testuser"
 	ntfyPass = "testpass"
-	sendNtfy("Auth Title", "Auth Message", "tag", "id", "3")
+	sendNtfy("Auth Title", "Auth Message", "tag", "id", "3", nil)
 }